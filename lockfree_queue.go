@@ -0,0 +1,381 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// lockFreeSpinLimit is how many times Put/Get retry the lock-free
+// fast path before parking on parkCv. It trades a little CPU for
+// avoiding a goroutine park/wake on every transient collision.
+const lockFreeSpinLimit = 1000
+
+// lfSlot is one ring buffer cell. sequence lets producers and
+// consumers agree on slot ownership without a lock: a producer may
+// write a slot once sequence == its tail index, a consumer may read
+// it once sequence == its head index + 1.
+type lfSlot[T any] struct {
+	sequence uint64
+	value    T
+}
+
+// LockFreeQueue is a BoundedQueue implemented as a Vyukov-style
+// bounded MPMC ring buffer. TryPut/TryGet are pure spin-CAS; the
+// blocking Put/Get fall back to parking on a condition variable after
+// a bounded spin, since genuinely unbounded spinning would burn a
+// core for every blocked goroutine.
+type LockFreeQueue[T any] struct {
+	mask  uint64
+	slots []lfSlot[T]
+	head  uint64 // consumer cursor, CAS-advanced by Get
+	tail  uint64 // producer cursor, CAS-advanced by Put
+
+	disposed uint32 // atomic: 0 = open, 1 = closed (hard or draining)
+	draining uint32 // atomic: 1 once CloseDrain is in effect
+
+	parkMtx sync.Mutex
+	parkCv  *sync.Cond
+}
+
+// NewLockFreeQueue is a factory for creating bounded queues backed by
+// a lock-free MPMC ring buffer. capacity must be a power of two so
+// index masking (pos & (capacity-1)) can replace the modulo used by
+// the cond-variable implementations.
+func NewLockFreeQueue[T any](capacity int) (BoundedQueue[T], error) {
+	if capacity <= 0 || capacity&(capacity-1) != 0 {
+		return nil, errors.New("capacity must be a power of two")
+	}
+
+	lfq := &LockFreeQueue[T]{
+		mask:  uint64(capacity - 1),
+		slots: make([]lfSlot[T], capacity),
+	}
+	for i := range lfq.slots {
+		lfq.slots[i].sequence = uint64(i)
+	}
+	lfq.parkCv = sync.NewCond(&lfq.parkMtx)
+
+	return lfq, nil
+}
+
+// wake nudges any goroutine parked in Put/Get so it retries the
+// lock-free fast path.
+func (lfq *LockFreeQueue[T]) wake() {
+	lfq.parkMtx.Lock()
+	lfq.parkCv.Broadcast()
+	lfq.parkMtx.Unlock()
+}
+
+// TryPut is the lock-free fast path: CAS-claim the tail slot, write
+// the value, then publish it by bumping the slot's sequence.
+func (lfq *LockFreeQueue[T]) TryPut(value T) error {
+	if atomic.LoadUint32(&lfq.disposed) != 0 {
+		return ErrDisposed
+	}
+
+	for {
+		tail := atomic.LoadUint64(&lfq.tail)
+		slot := &lfq.slots[tail&lfq.mask]
+		seq := atomic.LoadUint64(&slot.sequence)
+
+		diff := int64(seq) - int64(tail)
+		switch {
+		case diff == 0:
+			if atomic.CompareAndSwapUint64(&lfq.tail, tail, tail+1) {
+				slot.value = value
+				atomic.StoreUint64(&slot.sequence, tail+1)
+				lfq.wake()
+				return nil
+			}
+		case diff < 0:
+			return errors.New("queue is full")
+		default:
+			// another producer claimed this slot first; retry
+		}
+	}
+}
+
+// TryGet is the lock-free fast path: CAS-claim the head slot, read
+// the value, then free it for reuse by bumping its sequence past the
+// ring's capacity. A hard Close (disposed but not draining) is
+// rejected up front without claiming a slot, so it never consumes a
+// buffered value -- the same contract CondQueue's Get/TryGet use.
+func (lfq *LockFreeQueue[T]) TryGet() (T, error) {
+	var zero T
+
+	if atomic.LoadUint32(&lfq.disposed) != 0 && atomic.LoadUint32(&lfq.draining) == 0 {
+		return zero, ErrDisposed
+	}
+
+	for {
+		head := atomic.LoadUint64(&lfq.head)
+		slot := &lfq.slots[head&lfq.mask]
+		seq := atomic.LoadUint64(&slot.sequence)
+
+		diff := int64(seq) - int64(head+1)
+		switch {
+		case diff == 0:
+			if atomic.CompareAndSwapUint64(&lfq.head, head, head+1) {
+				value := slot.value
+				atomic.StoreUint64(&slot.sequence, head+uint64(len(lfq.slots)))
+				lfq.wake()
+				return value, nil
+			}
+		case diff < 0:
+			if atomic.LoadUint32(&lfq.disposed) != 0 {
+				return zero, ErrDisposed
+			}
+			return zero, errors.New("queue is empty")
+		default:
+			// another consumer claimed this slot first; retry
+		}
+	}
+}
+
+// hasSpace reports whether the current tail slot looks free. It is
+// used both as a cheap unlocked spin check and as the predicate
+// re-checked under parkMtx immediately before Wait.
+func (lfq *LockFreeQueue[T]) hasSpace() bool {
+	tail := atomic.LoadUint64(&lfq.tail)
+	slot := &lfq.slots[tail&lfq.mask]
+	return atomic.LoadUint64(&slot.sequence) == tail
+}
+
+// hasValue reports whether the current head slot looks filled. It is
+// used both as a cheap unlocked spin check and as the predicate
+// re-checked under parkMtx immediately before Wait.
+func (lfq *LockFreeQueue[T]) hasValue() bool {
+	head := atomic.LoadUint64(&lfq.head)
+	slot := &lfq.slots[head&lfq.mask]
+	return atomic.LoadUint64(&slot.sequence) == head+1
+}
+
+// Put adds value to the queue, spinning on TryPut before parking once
+// lockFreeSpinLimit attempts have collided with a full ring. The
+// predicate is re-checked while holding parkMtx, the same lock Wait
+// releases and reacquires, so a wake() from a concurrent Get can never
+// land in the gap between an unlocked check and the call to Wait.
+func (lfq *LockFreeQueue[T]) Put(value T) error {
+	for {
+		err := lfq.TryPut(value)
+		if err == nil || err == ErrDisposed {
+			return err
+		}
+
+		if lfq.parkForSpace() {
+			continue
+		}
+
+		lfq.parkMtx.Lock()
+		for !lfq.hasSpace() && atomic.LoadUint32(&lfq.disposed) == 0 {
+			lfq.parkCv.Wait()
+		}
+		lfq.parkMtx.Unlock()
+	}
+}
+
+// parkForSpace spins up to lockFreeSpinLimit times hoping a slot
+// frees up, returning true as soon as it looks like it might have.
+func (lfq *LockFreeQueue[T]) parkForSpace() bool {
+	for i := 0; i < lockFreeSpinLimit; i++ {
+		if lfq.hasSpace() {
+			return true
+		}
+	}
+	return false
+}
+
+// Get removes and returns the value at the head of the queue,
+// spinning on TryGet before parking once lockFreeSpinLimit attempts
+// have collided with an empty ring. The predicate is re-checked while
+// holding parkMtx immediately before Wait, for the same reason as Put.
+func (lfq *LockFreeQueue[T]) Get() (T, error) {
+	for {
+		value, err := lfq.TryGet()
+		if err == nil || err == ErrDisposed {
+			return value, err
+		}
+
+		if lfq.parkForValue() {
+			continue
+		}
+
+		lfq.parkMtx.Lock()
+		for !lfq.hasValue() && atomic.LoadUint32(&lfq.disposed) == 0 {
+			lfq.parkCv.Wait()
+		}
+		lfq.parkMtx.Unlock()
+	}
+}
+
+// parkForValue spins up to lockFreeSpinLimit times hoping a value
+// shows up, returning true as soon as it looks like it might have.
+func (lfq *LockFreeQueue[T]) parkForValue() bool {
+	for i := 0; i < lockFreeSpinLimit; i++ {
+		if lfq.hasValue() {
+			return true
+		}
+	}
+	return false
+}
+
+// PutTimeout adds value to the queue, blocking until there is room or
+// the timeout elapses.
+func (lfq *LockFreeQueue[T]) PutTimeout(value T, d time.Duration) error {
+	deadline := time.Now().Add(d)
+	timer := time.AfterFunc(d, lfq.wake)
+	defer timer.Stop()
+
+	for {
+		err := lfq.TryPut(value)
+		if err == nil || err == ErrDisposed {
+			return err
+		}
+
+		if lfq.parkForSpace() {
+			continue
+		}
+
+		lfq.parkMtx.Lock()
+		for !lfq.hasSpace() && atomic.LoadUint32(&lfq.disposed) == 0 {
+			if time.Now().After(deadline) {
+				lfq.parkMtx.Unlock()
+				return errors.New("put timed out")
+			}
+			lfq.parkCv.Wait()
+		}
+		lfq.parkMtx.Unlock()
+	}
+}
+
+// GetTimeout removes and returns the value at the head of the queue,
+// blocking until a value is available or the timeout elapses.
+func (lfq *LockFreeQueue[T]) GetTimeout(d time.Duration) (T, error) {
+	deadline := time.Now().Add(d)
+	timer := time.AfterFunc(d, lfq.wake)
+	defer timer.Stop()
+
+	for {
+		value, err := lfq.TryGet()
+		if err == nil || err == ErrDisposed {
+			return value, err
+		}
+
+		if lfq.parkForValue() {
+			continue
+		}
+
+		lfq.parkMtx.Lock()
+		for !lfq.hasValue() && atomic.LoadUint32(&lfq.disposed) == 0 {
+			if time.Now().After(deadline) {
+				lfq.parkMtx.Unlock()
+				var zero T
+				return zero, errors.New("get timed out")
+			}
+			lfq.parkCv.Wait()
+		}
+		lfq.parkMtx.Unlock()
+	}
+}
+
+// Poll removes and returns the value at the head of the queue without
+// blocking. The second return value is false if the queue was empty
+// or disposed by a hard Close; after CloseDrain it keeps returning
+// buffered values until the queue is empty, the same as TryGet.
+func (lfq *LockFreeQueue[T]) Poll() (T, bool) {
+	value, err := lfq.TryGet()
+	return value, err == nil
+}
+
+// Peek returns the value at the head of the queue without removing
+// it. Since no slot ownership is taken, the value is only a
+// best-effort snapshot under concurrent consumers.
+func (lfq *LockFreeQueue[T]) Peek() (T, bool) {
+	var zero T
+
+	head := atomic.LoadUint64(&lfq.head)
+	slot := &lfq.slots[head&lfq.mask]
+	if atomic.LoadUint64(&slot.sequence) != head+1 {
+		return zero, false
+	}
+
+	return slot.value, true
+}
+
+// Drain removes and returns every value currently in the queue. It
+// reads the ring directly instead of going through TryGet, because
+// TryGet rejects a hard-closed queue up front -- Drain needs to reach
+// a hard-closed queue's backlog the same way CondQueue.Drain and
+// ChanQueue.Drain do, rather than stranding it unreachably behind
+// Close.
+func (lfq *LockFreeQueue[T]) Drain() []T {
+	out := make([]T, 0, len(lfq.slots))
+	for {
+		head := atomic.LoadUint64(&lfq.head)
+		slot := &lfq.slots[head&lfq.mask]
+		seq := atomic.LoadUint64(&slot.sequence)
+
+		diff := int64(seq) - int64(head+1)
+		switch {
+		case diff == 0:
+			if atomic.CompareAndSwapUint64(&lfq.head, head, head+1) {
+				out = append(out, slot.value)
+				atomic.StoreUint64(&slot.sequence, head+uint64(len(lfq.slots)))
+				lfq.wake()
+			}
+		case diff < 0:
+			return out
+		default:
+			// another consumer claimed this slot first; retry
+		}
+	}
+}
+
+// Len is the current number of elements in the queue. It is a racy
+// snapshot of two independently updated cursors, accurate only when
+// no producer or consumer is concurrently active.
+func (lfq *LockFreeQueue[T]) Len() int {
+	tail := atomic.LoadUint64(&lfq.tail)
+	head := atomic.LoadUint64(&lfq.head)
+	return int(tail - head)
+}
+
+// Cap is the maximum number of elements the queue can hold
+func (lfq *LockFreeQueue[T]) Cap() int {
+	return len(lfq.slots)
+}
+
+// Close disposes of the queue. All in-flight and future Put/Get calls
+// return ErrDisposed, and every parked goroutine is woken. Whatever
+// was still buffered is left alone -- Len() keeps reporting it, the
+// same as CondQueue, ChanQueue and Skeleton -- since TryGet already
+// rejects a hard close up front without consuming a slot.
+func (lfq *LockFreeQueue[T]) Close() error {
+	if !atomic.CompareAndSwapUint32(&lfq.disposed, 0, 1) {
+		return nil
+	}
+
+	lfq.wake()
+	return nil
+}
+
+// CloseDrain disposes of the queue but lets Get continue to succeed
+// until the buffer is empty before it starts returning ErrDisposed.
+// Put still returns ErrDisposed right away.
+func (lfq *LockFreeQueue[T]) CloseDrain() error {
+	if !atomic.CompareAndSwapUint32(&lfq.disposed, 0, 1) {
+		return nil
+	}
+
+	atomic.StoreUint32(&lfq.draining, 1)
+	lfq.wake()
+	return nil
+}
+
+// String
+func (lfq *LockFreeQueue[T]) String() string {
+	return fmt.Sprintf("LockFree Len:%v Cap:%v", lfq.Len(), lfq.Cap())
+}
@@ -0,0 +1,210 @@
+package queue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockFreeQueueFIFOOrder(t *testing.T) {
+	q, err := NewLockFreeQueue[int](4)
+	if err != nil {
+		t.Fatalf("NewLockFreeQueue() = %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if err := q.TryPut(i); err != nil {
+			t.Fatalf("TryPut(%d) = %v", i, err)
+		}
+	}
+	for i := 1; i <= 3; i++ {
+		v, err := q.TryGet()
+		if err != nil || v != i {
+			t.Fatalf("TryGet() = (%v, %v), want (%d, nil)", v, err, i)
+		}
+	}
+}
+
+func TestLockFreeQueueRequiresPowerOfTwoCapacity(t *testing.T) {
+	if _, err := NewLockFreeQueue[int](3); err == nil {
+		t.Fatal("NewLockFreeQueue(3) = nil error, want non-nil")
+	}
+}
+
+// TestLockFreeQueueBlockedProducerConsumer exercises the park/wake
+// path directly: a full ring blocks Put, and Get on another goroutine
+// must wake it rather than leaving it parked forever. This is the
+// scenario the missed-wakeup race showed up in.
+func TestLockFreeQueueBlockedProducerConsumer(t *testing.T) {
+	q, err := NewLockFreeQueue[int](2)
+	if err != nil {
+		t.Fatalf("NewLockFreeQueue() = %v", err)
+	}
+	if err := q.Put(1); err != nil {
+		t.Fatalf("Put(1) = %v", err)
+	}
+	if err := q.Put(2); err != nil {
+		t.Fatalf("Put(2) = %v", err)
+	}
+
+	putDone := make(chan error, 1)
+	go func() {
+		putDone <- q.Put(3)
+	}()
+
+	// Give the producer time to actually park before freeing a slot.
+	time.Sleep(10 * time.Millisecond)
+
+	v, err := q.Get()
+	if err != nil || v != 1 {
+		t.Fatalf("Get() = (%v, %v), want (1, nil)", v, err)
+	}
+
+	select {
+	case err := <-putDone:
+		if err != nil {
+			t.Fatalf("blocked Put() returned %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("blocked Put() was never woken by the freed slot")
+	}
+
+	v, err = q.Get()
+	if err != nil || v != 2 {
+		t.Fatalf("Get() = (%v, %v), want (2, nil)", v, err)
+	}
+	v, err = q.Get()
+	if err != nil || v != 3 {
+		t.Fatalf("Get() = (%v, %v), want (3, nil)", v, err)
+	}
+}
+
+// TestLockFreeQueueManyBlockedPutsAndGets stresses the park/wake path
+// by pushing many values through a small ring between one producer
+// and one consumer, so each side parks many times, to catch a missed
+// wakeup that a single blocking handoff could get lucky on.
+func TestLockFreeQueueManyBlockedPutsAndGets(t *testing.T) {
+	const capacity = 4
+	const total = 500
+
+	q, err := NewLockFreeQueue[int](capacity)
+	if err != nil {
+		t.Fatalf("NewLockFreeQueue() = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < total; i++ {
+			if err := q.Put(i); err != nil {
+				t.Errorf("Put(%d) = %v", i, err)
+				return
+			}
+		}
+	}()
+
+	received := make([]int, 0, total)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < total; i++ {
+			v, err := q.Get()
+			if err != nil {
+				t.Errorf("Get() = %v", err)
+				return
+			}
+			received = append(received, v)
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("producer/consumer pair deadlocked, likely a missed wakeup")
+	}
+
+	if len(received) != total {
+		t.Fatalf("received %d values, want %d", len(received), total)
+	}
+	for i, v := range received {
+		if v != i {
+			t.Fatalf("received[%d] = %d, want %d (FIFO order violated)", i, v, i)
+		}
+	}
+}
+
+func TestLockFreeQueueCloseLeavesBufferAloneButRejectsGet(t *testing.T) {
+	q, err := NewLockFreeQueue[int](4)
+	if err != nil {
+		t.Fatalf("NewLockFreeQueue() = %v", err)
+	}
+	q.Put(1)
+	q.Put(2)
+
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close() = %v, want nil", err)
+	}
+	if got := q.Len(); got != 2 {
+		t.Fatalf("Len() after hard Close = %v, want 2 (buffer left alone)", got)
+	}
+	if _, err := q.Get(); err != ErrDisposed {
+		t.Fatalf("Get() after hard Close = %v, want ErrDisposed", err)
+	}
+	if _, err := q.TryGet(); err != ErrDisposed {
+		t.Fatalf("TryGet() after hard Close = %v, want ErrDisposed", err)
+	}
+}
+
+func TestLockFreeQueueDrainReachesBufferAfterHardClose(t *testing.T) {
+	q, err := NewLockFreeQueue[int](4)
+	if err != nil {
+		t.Fatalf("NewLockFreeQueue() = %v", err)
+	}
+	q.Put(1)
+	q.Put(2)
+
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close() = %v, want nil", err)
+	}
+
+	got := q.Drain()
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("Drain() after hard Close = %v, want [1 2]", got)
+	}
+	if l := q.Len(); l != 0 {
+		t.Fatalf("Len() after Drain() = %v, want 0", l)
+	}
+}
+
+func TestLockFreeQueueCloseDrainDrainsThenDisposes(t *testing.T) {
+	q, err := NewLockFreeQueue[int](4)
+	if err != nil {
+		t.Fatalf("NewLockFreeQueue() = %v", err)
+	}
+	q.Put(1)
+	q.Put(2)
+
+	if err := q.CloseDrain(); err != nil {
+		t.Fatalf("CloseDrain() = %v, want nil", err)
+	}
+	if err := q.Put(3); err != ErrDisposed {
+		t.Fatalf("Put() after CloseDrain = %v, want ErrDisposed", err)
+	}
+
+	if v, err := q.Get(); err != nil || v != 1 {
+		t.Fatalf("Get() while draining = (%v, %v), want (1, nil)", v, err)
+	}
+	if v, err := q.Get(); err != nil || v != 2 {
+		t.Fatalf("Get() while draining = (%v, %v), want (2, nil)", v, err)
+	}
+	if _, err := q.Get(); err != ErrDisposed {
+		t.Fatalf("Get() once drained = %v, want ErrDisposed", err)
+	}
+}
@@ -0,0 +1,26 @@
+package queue
+
+// Observer receives lifecycle notifications from a CondQueue so
+// callers can track saturation and blocking without holding the
+// queue's internal mutex. All methods are invoked with the queue's
+// mutex held, so implementations must not call back into the queue.
+type Observer interface {
+	// OnPut is called whenever a value is successfully added.
+	OnPut()
+
+	// OnGet is called whenever a value is successfully removed.
+	OnGet()
+
+	// OnBlock is called when a Put or Get is about to block.
+	OnBlock()
+
+	// OnUnblock is called when a blocked Put or Get is about to
+	// proceed after being woken.
+	OnUnblock()
+
+	// OnFull is called when a Put or TryPut finds the queue full.
+	OnFull()
+
+	// OnEmpty is called when a Get or TryGet finds the queue empty.
+	OnEmpty()
+}
@@ -0,0 +1,83 @@
+package queue
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrDisposed is returned by Put, Get, and their variants once the
+// queue has been closed. A queue closed with CloseDrain continues to
+// return buffered values from Get until it is empty, then returns
+// ErrDisposed; a queue closed with Close returns ErrDisposed
+// immediately.
+var ErrDisposed = errors.New("queue is disposed")
+
+// BoundedQueue is a fixed-capacity, thread-safe queue of elements of
+// type T. Implementations block on Put when the queue is full and on
+// Get when the queue is empty, mirroring the semantics of a buffered
+// Go channel.
+type BoundedQueue[T any] interface {
+	// Put adds value to the queue, blocking if the queue is full. It
+	// returns ErrDisposed if the queue has been closed.
+	Put(value T) error
+
+	// TryPut adds value to the queue without blocking. It returns an
+	// error if the queue is full, or ErrDisposed if the queue has
+	// been closed.
+	TryPut(value T) error
+
+	// PutTimeout adds value to the queue, blocking until there is
+	// room or the timeout elapses. It returns an error if the timeout
+	// elapses first.
+	PutTimeout(value T, d time.Duration) error
+
+	// Get removes and returns the value at the head of the queue,
+	// blocking if the queue is empty. It returns ErrDisposed if the
+	// queue has been closed and, for a drain-then-close, emptied.
+	Get() (T, error)
+
+	// TryGet removes and returns the value at the head of the queue
+	// without blocking. It returns an error if the queue is empty, or
+	// ErrDisposed per the rules described on Get.
+	TryGet() (T, error)
+
+	// GetTimeout removes and returns the value at the head of the
+	// queue, blocking until a value is available or the timeout
+	// elapses. It returns an error if the timeout elapses first.
+	GetTimeout(d time.Duration) (T, error)
+
+	// Poll removes and returns the value at the head of the queue
+	// without blocking. The second return value is false if the
+	// queue was empty.
+	Poll() (T, bool)
+
+	// Peek returns the value at the head of the queue without
+	// removing it. The second return value is false if the queue was
+	// empty.
+	Peek() (T, bool)
+
+	// Drain removes and returns all values currently in the queue,
+	// waking any blocked producers. CondQueue and Skeleton empty the
+	// buffer in one critical section; ChanQueue and LockFreeQueue
+	// remove values one at a time, so a value put or taken
+	// concurrently may or may not be part of the result.
+	Drain() []T
+
+	// Len is the current number of elements in the queue.
+	Len() int
+
+	// Cap is the maximum number of elements the queue can hold.
+	Cap() int
+
+	// Close disposes of the queue. All in-flight and future Put/Get
+	// calls return ErrDisposed, and all blocked goroutines are woken.
+	Close() error
+
+	// CloseDrain disposes of the queue like Close, except Get
+	// continues to succeed until the buffer is empty before it starts
+	// returning ErrDisposed. Put still returns ErrDisposed right away.
+	CloseDrain() error
+
+	// String returns a human readable summary of the queue's state.
+	String() string
+}
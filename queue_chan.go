@@ -0,0 +1,324 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ChanQueue is a BoundedQueue backed by channels used as counting
+// semaphores: slots tracks free capacity and items tracks buffered
+// values, so Put/Get can block the way a native channel does. The
+// values themselves live in a small mutex-protected ring rather than
+// in the channels, because a channel has no way to look at its head
+// without removing it -- receiving a value and sending it back would
+// put it at the tail instead of leaving it at the head, and can even
+// drop it if a concurrent send fills the channel first.
+type ChanQueue[T any] struct {
+	queue    []T
+	head     int
+	tail     int
+	length   int
+	capacity int
+
+	slots chan struct{} // one token per free slot; claimed before a write
+	items chan struct{} // one token per filled slot; claimed before a read
+
+	mu       sync.Mutex // guards queue/head/tail/length and disposed/draining
+	disposed bool
+	draining bool
+	doneCh   chan struct{} // closed by Close/CloseDrain to unblock Put/Get
+}
+
+func (chq *ChanQueue[T]) state() (disposed, draining bool) {
+	chq.mu.Lock()
+	defer chq.mu.Unlock()
+	return chq.disposed, chq.draining
+}
+
+// push writes value at the tail of the ring. Callers must have
+// already claimed a slots token.
+func (chq *ChanQueue[T]) push(value T) {
+	chq.mu.Lock()
+	chq.queue[chq.tail] = value
+	chq.tail = (chq.tail + 1) % chq.capacity
+	chq.length++
+	chq.mu.Unlock()
+}
+
+// pop removes and returns the value at the head of the ring. Callers
+// must have already claimed an items token; the freed slot's token is
+// handed back to slots so a producer can claim it.
+func (chq *ChanQueue[T]) pop() T {
+	chq.mu.Lock()
+	value := chq.queue[chq.head]
+	chq.head = (chq.head + 1) % chq.capacity
+	chq.length--
+	chq.mu.Unlock()
+	chq.slots <- struct{}{}
+	return value
+}
+
+// TryPut adds an element onto the tail queue
+// if the queue is full, an error is returned
+func (chq *ChanQueue[T]) TryPut(value T) error {
+	if disposed, _ := chq.state(); disposed {
+		return ErrDisposed
+	}
+
+	select {
+	case <-chq.slots:
+	default:
+		return errors.New("queue is full")
+	}
+
+	chq.push(value)
+	chq.items <- struct{}{}
+	return nil
+}
+
+// Put adds an element onto the tail queue
+// if the queue is full the function blocks. Disposal is checked up
+// front, and a Put blocked waiting for a slot also wakes on doneCh, so
+// a Close that lands while it is blocked returns ErrDisposed.
+func (chq *ChanQueue[T]) Put(value T) error {
+	if disposed, _ := chq.state(); disposed {
+		return ErrDisposed
+	}
+
+	select {
+	case <-chq.slots:
+	case <-chq.doneCh:
+		return ErrDisposed
+	}
+
+	chq.push(value)
+	chq.items <- struct{}{}
+	return nil
+}
+
+// PutTimeout adds an element onto the tail queue, blocking until there
+// is room or the timeout elapses.
+func (chq *ChanQueue[T]) PutTimeout(value T, d time.Duration) error {
+	if disposed, _ := chq.state(); disposed {
+		return ErrDisposed
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-chq.slots:
+	case <-chq.doneCh:
+		return ErrDisposed
+	case <-timer.C:
+		return errors.New("put timed out")
+	}
+
+	chq.push(value)
+	chq.items <- struct{}{}
+	return nil
+}
+
+// Get returns an element from the head of the queue
+// if the queue is empty, the caller blocks. Once the queue is
+// disposed it returns ErrDisposed: immediately for a hard Close, or
+// once the buffer is empty for a CloseDrain.
+func (chq *ChanQueue[T]) Get() (T, error) {
+	var zero T
+
+	disposed, draining := chq.state()
+	if disposed && !draining {
+		return zero, ErrDisposed
+	}
+
+	if draining {
+		select {
+		case <-chq.items:
+			return chq.pop(), nil
+		default:
+			return zero, ErrDisposed
+		}
+	}
+
+	select {
+	case <-chq.items:
+		return chq.pop(), nil
+	case <-chq.doneCh:
+		return zero, ErrDisposed
+	}
+}
+
+// TryGet gets a value or returns an error if the queue is empty. It
+// returns ErrDisposed per the rules described on Get.
+func (chq *ChanQueue[T]) TryGet() (T, error) {
+	var zero T
+
+	disposed, draining := chq.state()
+	if disposed && !draining {
+		return zero, ErrDisposed
+	}
+
+	select {
+	case <-chq.items:
+		return chq.pop(), nil
+	default:
+	}
+
+	if disposed {
+		return zero, ErrDisposed
+	}
+	return zero, errors.New("queue is empty")
+}
+
+// GetTimeout removes and returns the element at the head of the
+// queue, blocking until a value is available or the timeout elapses.
+func (chq *ChanQueue[T]) GetTimeout(d time.Duration) (T, error) {
+	var zero T
+
+	disposed, draining := chq.state()
+	if disposed && !draining {
+		return zero, ErrDisposed
+	}
+
+	if draining {
+		select {
+		case <-chq.items:
+			return chq.pop(), nil
+		default:
+			return zero, ErrDisposed
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-chq.items:
+		return chq.pop(), nil
+	case <-chq.doneCh:
+		return zero, ErrDisposed
+	case <-timer.C:
+		return zero, errors.New("get timed out")
+	}
+}
+
+// Poll removes and returns the element at the head of the queue
+// without blocking. The second return value is false if the queue
+// was empty, or if the queue was disposed by a hard Close (a
+// CloseDrain lets Poll keep draining what remains, the same as
+// Get/TryGet).
+func (chq *ChanQueue[T]) Poll() (T, bool) {
+	if disposed, draining := chq.state(); disposed && !draining {
+		var zero T
+		return zero, false
+	}
+
+	select {
+	case <-chq.items:
+		return chq.pop(), true
+	default:
+		var zero T
+		return zero, false
+	}
+}
+
+// Peek returns the element at the head of the queue without removing
+// it. Unlike a native channel, the ring keeps the head addressable, so
+// this is a real peek: it never disturbs ordering and never drops a
+// value the way receiving-then-resending on a channel can. The second
+// return value is false if the queue was empty, or if the queue was
+// disposed by a hard Close, matching Poll/Get/TryGet.
+func (chq *ChanQueue[T]) Peek() (T, bool) {
+	chq.mu.Lock()
+	defer chq.mu.Unlock()
+
+	var zero T
+	if chq.disposed && !chq.draining {
+		return zero, false
+	}
+	if chq.length == 0 {
+		return zero, false
+	}
+	return chq.queue[chq.head], true
+}
+
+// Drain removes and returns every element currently buffered in the
+// queue.
+func (chq *ChanQueue[T]) Drain() []T {
+	out := make([]T, 0, chq.Len())
+	for {
+		select {
+		case <-chq.items:
+			out = append(out, chq.pop())
+		default:
+			return out
+		}
+	}
+}
+
+// Len is the current number of elements in the queue
+func (chq *ChanQueue[T]) Len() int {
+	chq.mu.Lock()
+	defer chq.mu.Unlock()
+	return chq.length
+}
+
+// Cap is the maximum number of elements the queue can hold
+func (chq *ChanQueue[T]) Cap() int {
+	return chq.capacity
+}
+
+// Close disposes of the queue. All in-flight and future Put/Get calls
+// return ErrDisposed, and every blocked goroutine is woken.
+func (chq *ChanQueue[T]) Close() error {
+	chq.mu.Lock()
+	if chq.disposed {
+		chq.mu.Unlock()
+		return nil
+	}
+	chq.disposed = true
+	chq.mu.Unlock()
+
+	close(chq.doneCh)
+	return nil
+}
+
+// CloseDrain disposes of the queue but lets Get continue to succeed
+// until the buffer is empty before it starts returning ErrDisposed.
+// Put still returns ErrDisposed right away.
+func (chq *ChanQueue[T]) CloseDrain() error {
+	chq.mu.Lock()
+	if chq.disposed {
+		chq.mu.Unlock()
+		return nil
+	}
+	chq.disposed = true
+	chq.draining = true
+	chq.mu.Unlock()
+
+	close(chq.doneCh)
+	return nil
+}
+
+// String
+func (chq *ChanQueue[T]) String() string {
+	return fmt.Sprintf("Chan Len:%v Cap:%v", chq.Len(), chq.Cap())
+}
+
+// NewChanQueue is a factory for creating bounded queues backed by
+// channels. It returns an instance of BoundedQueue.
+func NewChanQueue[T any](size int) BoundedQueue[T] {
+	chq := &ChanQueue[T]{
+		queue:    make([]T, size),
+		capacity: size,
+		slots:    make(chan struct{}, size),
+		items:    make(chan struct{}, size),
+		doneCh:   make(chan struct{}),
+	}
+	for i := 0; i < size; i++ {
+		chq.slots <- struct{}{}
+	}
+	return chq
+}
@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChanQueueCloseRejectsPutImmediately(t *testing.T) {
+	chq := NewChanQueue[int](4)
+
+	if err := chq.Close(); err != nil {
+		t.Fatalf("Close() = %v, want nil", err)
+	}
+	if err := chq.Put(1); err != ErrDisposed {
+		t.Fatalf("Put() after Close = %v, want ErrDisposed", err)
+	}
+	if err := chq.TryPut(1); err != ErrDisposed {
+		t.Fatalf("TryPut() after Close = %v, want ErrDisposed", err)
+	}
+}
+
+func TestChanQueueCloseLeavesBufferAloneButRejectsGet(t *testing.T) {
+	chq := NewChanQueue[int](4)
+	chq.Put(1)
+	chq.Put(2)
+
+	if err := chq.Close(); err != nil {
+		t.Fatalf("Close() = %v, want nil", err)
+	}
+	if got := chq.Len(); got != 2 {
+		t.Fatalf("Len() after hard Close = %v, want 2 (buffer left alone)", got)
+	}
+
+	// All four read paths must refuse a hard-closed queue every time,
+	// not just when a race against doneCh happens to go the right way.
+	for i := 0; i < 50; i++ {
+		if _, err := chq.TryGet(); err != ErrDisposed {
+			t.Fatalf("TryGet() after hard Close = %v, want ErrDisposed", err)
+		}
+		if _, err := chq.Get(); err != ErrDisposed {
+			t.Fatalf("Get() after hard Close = %v, want ErrDisposed", err)
+		}
+		if _, ok := chq.Poll(); ok {
+			t.Fatal("Poll() after hard Close returned a value, want false")
+		}
+		if _, ok := chq.Peek(); ok {
+			t.Fatal("Peek() after hard Close returned a value, want false")
+		}
+	}
+	if got := chq.Len(); got != 2 {
+		t.Fatalf("Len() after failed reads = %v, want 2 (still untouched)", got)
+	}
+}
+
+func TestChanQueueCloseDrainDrainsThenDisposes(t *testing.T) {
+	chq := NewChanQueue[int](4)
+	chq.Put(1)
+	chq.Put(2)
+
+	if err := chq.CloseDrain(); err != nil {
+		t.Fatalf("CloseDrain() = %v, want nil", err)
+	}
+	if err := chq.Put(3); err != ErrDisposed {
+		t.Fatalf("Put() after CloseDrain = %v, want ErrDisposed", err)
+	}
+
+	if v, ok := chq.Peek(); !ok || v != 1 {
+		t.Fatalf("Peek() while draining = (%v, %v), want (1, true)", v, ok)
+	}
+	if v, ok := chq.Poll(); !ok || v != 1 {
+		t.Fatalf("Poll() while draining = (%v, %v), want (1, true)", v, ok)
+	}
+	if v, err := chq.Get(); err != nil || v != 2 {
+		t.Fatalf("Get() while draining = (%v, %v), want (2, nil)", v, err)
+	}
+	if _, err := chq.Get(); err != ErrDisposed {
+		t.Fatalf("Get() once drained = %v, want ErrDisposed", err)
+	}
+}
+
+func TestChanQueueCloseUnblocksWaitingGet(t *testing.T) {
+	chq := NewChanQueue[int](1)
+	result := make(chan error, 1)
+
+	go func() {
+		_, err := chq.Get()
+		result <- err
+	}()
+
+	// Give the goroutine time to actually park in the blocking select.
+	time.Sleep(10 * time.Millisecond)
+
+	chq.Close()
+
+	select {
+	case err := <-result:
+		if err != ErrDisposed {
+			t.Fatalf("blocked Get() unblocked with %v, want ErrDisposed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("blocked Get() was not unblocked by Close()")
+	}
+}
+
+func TestChanQueueCloseUnblocksWaitingPut(t *testing.T) {
+	chq := NewChanQueue[int](1)
+	chq.Put(1) // fill the queue so the next Put blocks
+
+	result := make(chan error, 1)
+	go func() {
+		result <- chq.Put(2)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	chq.Close()
+
+	select {
+	case err := <-result:
+		if err != ErrDisposed {
+			t.Fatalf("blocked Put() unblocked with %v, want ErrDisposed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("blocked Put() was not unblocked by Close()")
+	}
+}
@@ -0,0 +1,584 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CondQueue is a BoundedQueue that uses a mutex and condition
+// variables over a circular buffer. This implementation is intended
+// to be thread safe.
+type CondQueue[T any] struct {
+	queue    []T
+	head     int
+	tail     int
+	length   int
+	capacity int
+	disposed bool         // set by Close/CloseDrain; rejects Put, gates Get
+	draining bool         // true once CloseDrain lets Get drain what remains
+	mtx      sync.Mutex   // a mutex for mutual exclusion
+	putcv    *sync.Cond   // a condition variable for controlling Puts
+	getcv    *sync.Cond   // a condition variable for controlling Gets
+
+	obs Observer // optional; see NewCondQueueWithObserver
+
+	// counters are updated under mtx but read via sync/atomic so
+	// callers can inspect saturation without taking the lock.
+	putsTotal        uint64
+	getsTotal        uint64
+	blockedProducers int64
+	blockedConsumers int64
+	highWaterMark    uint64
+}
+
+// PutsTotal is the number of values successfully put onto the queue.
+func (cq *CondQueue[T]) PutsTotal() uint64 { return atomic.LoadUint64(&cq.putsTotal) }
+
+// GetsTotal is the number of values successfully taken off the queue.
+func (cq *CondQueue[T]) GetsTotal() uint64 { return atomic.LoadUint64(&cq.getsTotal) }
+
+// BlockedProducers is the number of goroutines currently blocked in Put.
+func (cq *CondQueue[T]) BlockedProducers() int64 { return atomic.LoadInt64(&cq.blockedProducers) }
+
+// BlockedConsumers is the number of goroutines currently blocked in Get.
+func (cq *CondQueue[T]) BlockedConsumers() int64 { return atomic.LoadInt64(&cq.blockedConsumers) }
+
+// HighWaterMark is the largest length the queue has reached.
+func (cq *CondQueue[T]) HighWaterMark() uint64 { return atomic.LoadUint64(&cq.highWaterMark) }
+
+// recordPut updates the put counters and high water mark, and fires
+// obs.OnPut if an Observer is attached. Callers must hold the mutex.
+func (cq *CondQueue[T]) recordPut() {
+	atomic.AddUint64(&cq.putsTotal, 1)
+	if uint64(cq.length) > atomic.LoadUint64(&cq.highWaterMark) {
+		atomic.StoreUint64(&cq.highWaterMark, uint64(cq.length))
+	}
+	if cq.obs != nil {
+		cq.obs.OnPut()
+	}
+}
+
+// recordGet updates the get counters and fires obs.OnGet if an
+// Observer is attached. Callers must hold the mutex.
+func (cq *CondQueue[T]) recordGet() {
+	atomic.AddUint64(&cq.getsTotal, 1)
+	if cq.obs != nil {
+		cq.obs.OnGet()
+	}
+}
+
+// TryPut adds an element onto the tail queue
+// if the queue is full, an error is returned
+func (cq *CondQueue[T]) TryPut(value T) error {
+	// lock the mutex
+	cq.putcv.L.Lock()
+	defer cq.putcv.L.Unlock()
+
+	if cq.disposed {
+		return ErrDisposed
+	}
+
+	// is queue full ?
+	if cq.length == cq.capacity {
+		if cq.obs != nil {
+			cq.obs.OnFull()
+		}
+		// return an error
+		e := errors.New("queue is full")
+		return e
+	}
+
+	// queue had room, add it at the tail
+	cq.queue[cq.tail] = value
+	cq.tail = (cq.tail + 1) % cq.capacity
+	cq.length++
+	cq.recordPut()
+
+	// signal a Get to wake up
+	cq.getcv.Signal()
+
+	// no error
+	return nil
+}
+
+// Put adds an element onto the tail queue
+// if the queue is full the function blocks
+func (cq *CondQueue[T]) Put(value T) error {
+	// lock the mutex
+	cq.putcv.L.Lock()
+	defer cq.putcv.L.Unlock()
+
+	// block until there is room in the queue
+	if cq.length == cq.capacity && !cq.disposed {
+		if cq.obs != nil {
+			cq.obs.OnFull()
+			cq.obs.OnBlock()
+		}
+		atomic.AddInt64(&cq.blockedProducers, 1)
+		for cq.length == cq.capacity && !cq.disposed {
+			// release and wait
+			cq.putcv.Wait()
+		}
+		atomic.AddInt64(&cq.blockedProducers, -1)
+		if cq.obs != nil {
+			cq.obs.OnUnblock()
+		}
+	}
+
+	if cq.disposed {
+		return ErrDisposed
+	}
+
+	// queue has room, add it at the tail
+	cq.queue[cq.tail] = value
+	cq.tail = (cq.tail + 1) % cq.capacity
+	cq.length++
+	cq.recordPut()
+
+	// signal a Get to wake up
+	cq.getcv.Signal()
+
+	return nil
+}
+
+// Get returns an element from the head of the queue
+// if the queue is empty, the caller blocks. Once the queue is
+// disposed it returns ErrDisposed: immediately for a hard Close, or
+// once the buffer is empty for a CloseDrain.
+func (cq *CondQueue[T]) Get() (T, error) {
+	// lock the mutex
+	cq.getcv.L.Lock()
+	defer cq.getcv.L.Unlock()
+
+	var zero T
+
+	if cq.disposed && !cq.draining {
+		return zero, ErrDisposed
+	}
+
+	// block until a value is in the queue
+	if cq.length == 0 {
+		if cq.obs != nil {
+			cq.obs.OnEmpty()
+			cq.obs.OnBlock()
+		}
+		atomic.AddInt64(&cq.blockedConsumers, 1)
+		for cq.length == 0 {
+			if cq.disposed {
+				atomic.AddInt64(&cq.blockedConsumers, -1)
+				return zero, ErrDisposed
+			}
+			// release and wait
+			cq.getcv.Wait()
+		}
+		atomic.AddInt64(&cq.blockedConsumers, -1)
+		if cq.obs != nil {
+			cq.obs.OnUnblock()
+		}
+	}
+
+	// at this point there is at least one item in the queue
+	// remove the head
+	value := cq.queue[cq.head]
+	cq.head = (cq.head + 1) % cq.capacity
+	cq.length--
+	cq.recordGet()
+
+	// signal a Put to wake up
+	cq.putcv.Signal()
+
+	return value, nil
+}
+
+// PutTimeout adds an element onto the tail queue, blocking until
+// there is room or the timeout elapses. Since sync.Cond has no native
+// timed wait, the timeout is enforced with a timer goroutine that
+// broadcasts putcv on expiry.
+func (cq *CondQueue[T]) PutTimeout(value T, d time.Duration) error {
+	cq.putcv.L.Lock()
+	defer cq.putcv.L.Unlock()
+
+	if cq.disposed {
+		return ErrDisposed
+	}
+
+	if cq.length == cq.capacity {
+		if cq.obs != nil {
+			cq.obs.OnFull()
+			cq.obs.OnBlock()
+		}
+
+		timedOut := false
+		timer := time.AfterFunc(d, func() {
+			cq.putcv.L.Lock()
+			timedOut = true
+			cq.putcv.L.Unlock()
+			cq.putcv.Broadcast()
+		})
+		defer timer.Stop()
+
+		atomic.AddInt64(&cq.blockedProducers, 1)
+		for cq.length == cq.capacity && !timedOut && !cq.disposed {
+			cq.putcv.Wait()
+		}
+		atomic.AddInt64(&cq.blockedProducers, -1)
+		if cq.obs != nil {
+			cq.obs.OnUnblock()
+		}
+
+		if cq.disposed {
+			return ErrDisposed
+		}
+
+		if timedOut {
+			return errors.New("put timed out")
+		}
+	}
+
+	cq.queue[cq.tail] = value
+	cq.tail = (cq.tail + 1) % cq.capacity
+	cq.length++
+	cq.recordPut()
+
+	cq.getcv.Signal()
+
+	return nil
+}
+
+// TryGet gets a value or returns an error if the queue is empty. It
+// returns ErrDisposed per the rules described on Get.
+func (cq *CondQueue[T]) TryGet() (T, error) {
+	var value T
+	var err error
+
+	// lock the mutex
+	cq.getcv.L.Lock()
+	defer cq.getcv.L.Unlock()
+
+	if cq.disposed && !cq.draining {
+		return value, ErrDisposed
+	}
+
+	// is the queue empty?
+	if cq.length > 0 {
+		value = cq.queue[cq.head]
+		cq.head = (cq.head + 1) % cq.capacity
+		cq.length--
+		cq.recordGet()
+	} else if cq.disposed {
+		return value, ErrDisposed
+	} else {
+		if cq.obs != nil {
+			cq.obs.OnEmpty()
+		}
+		err = errors.New("queue is empty")
+	}
+
+	// signal a Put to wake up
+	cq.putcv.Signal()
+
+	return value, err
+}
+
+// GetTimeout removes and returns the value at the head of the queue,
+// blocking until a value is available or the timeout elapses. The
+// timeout is enforced the same way as PutTimeout: a timer goroutine
+// broadcasts getcv on expiry.
+func (cq *CondQueue[T]) GetTimeout(d time.Duration) (T, error) {
+	cq.getcv.L.Lock()
+	defer cq.getcv.L.Unlock()
+
+	var zero T
+
+	if cq.disposed && !cq.draining {
+		return zero, ErrDisposed
+	}
+
+	if cq.length == 0 {
+		if cq.obs != nil {
+			cq.obs.OnEmpty()
+			cq.obs.OnBlock()
+		}
+
+		timedOut := false
+		timer := time.AfterFunc(d, func() {
+			cq.getcv.L.Lock()
+			timedOut = true
+			cq.getcv.L.Unlock()
+			cq.getcv.Broadcast()
+		})
+		defer timer.Stop()
+
+		atomic.AddInt64(&cq.blockedConsumers, 1)
+		for cq.length == 0 && !timedOut && !cq.disposed {
+			cq.getcv.Wait()
+		}
+		atomic.AddInt64(&cq.blockedConsumers, -1)
+		if cq.obs != nil {
+			cq.obs.OnUnblock()
+		}
+
+		if cq.length == 0 && cq.disposed {
+			return zero, ErrDisposed
+		}
+
+		if timedOut {
+			return zero, errors.New("get timed out")
+		}
+	}
+
+	value := cq.queue[cq.head]
+	cq.head = (cq.head + 1) % cq.capacity
+	cq.length--
+	cq.recordGet()
+
+	cq.putcv.Signal()
+
+	return value, nil
+}
+
+// Poll removes and returns the value at the head of the queue without
+// blocking. The second return value is false if the queue was empty,
+// or if the queue was disposed by a hard Close (a CloseDrain lets
+// Poll keep draining what remains, the same as Get/TryGet).
+func (cq *CondQueue[T]) Poll() (T, bool) {
+	cq.getcv.L.Lock()
+	defer cq.getcv.L.Unlock()
+
+	var value T
+	if cq.disposed && !cq.draining {
+		return value, false
+	}
+	if cq.length == 0 {
+		return value, false
+	}
+
+	value = cq.queue[cq.head]
+	cq.head = (cq.head + 1) % cq.capacity
+	cq.length--
+	cq.recordGet()
+
+	cq.putcv.Signal()
+
+	return value, true
+}
+
+// Peek returns the value at the head of the queue without removing
+// it. The second return value is false if the queue was empty, or if
+// the queue was disposed by a hard Close, matching Poll/Get/TryGet.
+func (cq *CondQueue[T]) Peek() (T, bool) {
+	cq.getcv.L.Lock()
+	defer cq.getcv.L.Unlock()
+
+	var value T
+	if cq.disposed && !cq.draining {
+		return value, false
+	}
+	if cq.length == 0 {
+		return value, false
+	}
+
+	return cq.queue[cq.head], true
+}
+
+// Drain atomically empties the buffer and returns everything it held,
+// waking all blocked producers.
+func (cq *CondQueue[T]) Drain() []T {
+	cq.mtx.Lock()
+	defer cq.mtx.Unlock()
+
+	out := make([]T, cq.length)
+	for i := 0; i < cq.length; i++ {
+		out[i] = cq.queue[(cq.head+i)%cq.capacity]
+	}
+	cq.head = 0
+	cq.tail = 0
+	cq.length = 0
+
+	cq.putcv.Broadcast()
+
+	return out
+}
+
+// PutN copies as many values as fit into the queue in a single
+// critical section, blocking until at least one slot is free. It
+// returns the number of values copied, which may be less than
+// len(values) if the queue does not have room for all of them. This
+// collapses what would otherwise be len(values) lock acquisitions and
+// cond signals into one.
+func (cq *CondQueue[T]) PutN(values []T) (int, error) {
+	cq.putcv.L.Lock()
+	defer cq.putcv.L.Unlock()
+
+	if cq.length == cq.capacity && !cq.disposed {
+		if cq.obs != nil {
+			cq.obs.OnFull()
+			cq.obs.OnBlock()
+		}
+		atomic.AddInt64(&cq.blockedProducers, 1)
+		for cq.length == cq.capacity && !cq.disposed {
+			cq.putcv.Wait()
+		}
+		atomic.AddInt64(&cq.blockedProducers, -1)
+		if cq.obs != nil {
+			cq.obs.OnUnblock()
+		}
+	}
+
+	if cq.disposed {
+		return 0, ErrDisposed
+	}
+
+	room := cq.capacity - cq.length
+	n := len(values)
+	if n > room {
+		n = room
+	}
+
+	for i := 0; i < n; i++ {
+		cq.queue[cq.tail] = values[i]
+		cq.tail = (cq.tail + 1) % cq.capacity
+		cq.length++
+		cq.recordPut()
+	}
+
+	cq.getcv.Broadcast()
+
+	return n, nil
+}
+
+// GetN blocks until at least min values are available, then drains up
+// to len(dst) values from the queue in a single critical section. It
+// returns the number of values copied into dst. This collapses what
+// would otherwise be len(dst) lock acquisitions and cond signals into
+// one.
+func (cq *CondQueue[T]) GetN(dst []T, min int) (int, error) {
+	if min > len(dst) {
+		min = len(dst)
+	}
+
+	cq.getcv.L.Lock()
+	defer cq.getcv.L.Unlock()
+
+	if cq.disposed && !cq.draining {
+		return 0, ErrDisposed
+	}
+
+	if cq.length < min {
+		if cq.obs != nil {
+			cq.obs.OnEmpty()
+			cq.obs.OnBlock()
+		}
+		atomic.AddInt64(&cq.blockedConsumers, 1)
+		for cq.length < min {
+			if cq.disposed {
+				if cq.draining && cq.length > 0 {
+					break
+				}
+				atomic.AddInt64(&cq.blockedConsumers, -1)
+				return 0, ErrDisposed
+			}
+			cq.getcv.Wait()
+		}
+		atomic.AddInt64(&cq.blockedConsumers, -1)
+		if cq.obs != nil {
+			cq.obs.OnUnblock()
+		}
+	}
+
+	n := len(dst)
+	if n > cq.length {
+		n = cq.length
+	}
+
+	for i := 0; i < n; i++ {
+		dst[i] = cq.queue[cq.head]
+		cq.head = (cq.head + 1) % cq.capacity
+		cq.length--
+		cq.recordGet()
+	}
+
+	cq.putcv.Broadcast()
+
+	return n, nil
+}
+
+// Len is the current number of elements in the queue
+func (cq *CondQueue[T]) Len() int {
+	return cq.length
+}
+
+// Cap is the maximum number of elements the queue can hold
+func (cq *CondQueue[T]) Cap() int {
+	return cap(cq.queue)
+}
+
+// Close disposes of the queue. All in-flight and future Put/Get calls
+// return ErrDisposed, and every blocked goroutine is woken.
+func (cq *CondQueue[T]) Close() error {
+	cq.mtx.Lock()
+	cq.disposed = true
+	cq.draining = false
+	cq.mtx.Unlock()
+
+	cq.putcv.Broadcast()
+	cq.getcv.Broadcast()
+
+	return nil
+}
+
+// CloseDrain disposes of the queue but lets Get continue to succeed
+// until the buffer is empty before it starts returning ErrDisposed.
+// Put still returns ErrDisposed right away.
+func (cq *CondQueue[T]) CloseDrain() error {
+	cq.mtx.Lock()
+	cq.disposed = true
+	cq.draining = true
+	cq.mtx.Unlock()
+
+	cq.putcv.Broadcast()
+	cq.getcv.Broadcast()
+
+	return nil
+}
+
+// String
+func (cq *CondQueue[T]) String() string {
+	return fmt.Sprintf(
+		"Cond Len:%v Cap:%v Puts:%v Gets:%v BlockedProducers:%v BlockedConsumers:%v HighWaterMark:%v",
+		cq.Len(), cq.Cap(), cq.PutsTotal(), cq.GetsTotal(),
+		cq.BlockedProducers(), cq.BlockedConsumers(), cq.HighWaterMark(),
+	)
+}
+
+// NewCondQueue is a factory for creating bounded queues that use a
+// condition variable and circular buffer. It returns an instance of
+// BoundedQueue.
+func NewCondQueue[T any](size int) BoundedQueue[T] {
+	return NewCondQueueWithObserver[T](size, nil)
+}
+
+// NewCondQueueWithObserver is a factory for creating bounded queues
+// that use a condition variable and circular buffer, with an Observer
+// attached to receive lifecycle notifications. obs may be nil, in
+// which case it behaves exactly like NewCondQueue. It returns an
+// instance of BoundedQueue.
+func NewCondQueueWithObserver[T any](size int, obs Observer) BoundedQueue[T] {
+	var cq CondQueue[T]
+
+	// allocate the whole slice during init
+	cq.queue = make([]T, size, size)
+	cq.head = 0
+	cq.tail = 0
+	cq.length = 0
+	cq.capacity = size
+	cq.mtx = sync.Mutex{}
+	cq.putcv = sync.NewCond(&cq.mtx)
+	cq.getcv = sync.NewCond(&cq.mtx)
+	cq.obs = obs
+
+	return &cq
+}
@@ -0,0 +1,89 @@
+package queue
+
+import "testing"
+
+func TestCondQueuePutNCopiesUpToCapacity(t *testing.T) {
+	cq := NewCondQueue[int](4)
+
+	n, err := cq.(*CondQueue[int]).PutN([]int{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatalf("PutN() = (%v, %v), want (4, nil)", n, err)
+	}
+	if n != 4 {
+		t.Fatalf("PutN() copied %d values, want 4 (capped at capacity)", n)
+	}
+	if got := cq.Len(); got != 4 {
+		t.Fatalf("Len() = %v, want 4", got)
+	}
+}
+
+func TestCondQueueGetNDrainsUpToLength(t *testing.T) {
+	cq := NewCondQueue[int](4)
+	cq.Put(1)
+	cq.Put(2)
+	cq.Put(3)
+
+	dst := make([]int, 4)
+	n, err := cq.(*CondQueue[int]).GetN(dst, 1)
+	if err != nil {
+		t.Fatalf("GetN() = (%v, %v), want (3, nil)", n, err)
+	}
+	if n != 3 {
+		t.Fatalf("GetN() copied %d values, want 3", n)
+	}
+	for i, want := range []int{1, 2, 3} {
+		if dst[i] != want {
+			t.Fatalf("dst[%d] = %d, want %d", i, dst[i], want)
+		}
+	}
+}
+
+func TestCondQueuePutNRejectsHardClosedQueue(t *testing.T) {
+	cq := NewCondQueue[int](4)
+	cq.Close()
+
+	n, err := cq.(*CondQueue[int]).PutN([]int{1, 2})
+	if err != ErrDisposed {
+		t.Fatalf("PutN() after hard Close = (%v, %v), want (0, ErrDisposed)", n, err)
+	}
+}
+
+// TestCondQueueGetNRejectsHardClosedQueueEvenWhenMinIsAlreadyMet
+// covers the case where cq.length >= min before GetN ever waits --
+// the disposed check must run regardless of whether the wait loop
+// body executes, the same way PutN's does.
+func TestCondQueueGetNRejectsHardClosedQueueEvenWhenMinIsAlreadyMet(t *testing.T) {
+	cq := NewCondQueue[int](4)
+	cq.Put(1)
+	cq.Close()
+
+	dst := make([]int, 1)
+	n, err := cq.(*CondQueue[int]).GetN(dst, 1)
+	if err != ErrDisposed {
+		t.Fatalf("GetN() after hard Close = (%v, %v), want (0, ErrDisposed)", n, err)
+	}
+
+	// min == 0 is the other way the wait loop body can be skipped.
+	n, err = cq.(*CondQueue[int]).GetN(dst, 0)
+	if err != ErrDisposed {
+		t.Fatalf("GetN(min=0) after hard Close = (%v, %v), want (0, ErrDisposed)", n, err)
+	}
+}
+
+func TestCondQueueGetNDrainsThenDisposesOnCloseDrain(t *testing.T) {
+	cq := NewCondQueue[int](4)
+	cq.Put(1)
+	cq.Put(2)
+	cq.CloseDrain()
+
+	dst := make([]int, 4)
+	n, err := cq.(*CondQueue[int]).GetN(dst, 0)
+	if err != nil || n != 2 {
+		t.Fatalf("GetN() while draining = (%v, %v), want (2, nil)", n, err)
+	}
+
+	n, err = cq.(*CondQueue[int]).GetN(dst, 1)
+	if err != ErrDisposed {
+		t.Fatalf("GetN() once drained = (%v, %v), want (0, ErrDisposed)", n, err)
+	}
+}
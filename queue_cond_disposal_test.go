@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+// waitFor polls until cond returns true or the deadline passes,
+// failing the test on timeout. It exists because a goroutine started
+// to block in a queue call reaches its wait asynchronously, so tests
+// wait on an observable condition (a blocked-goroutine counter or an
+// observer count) instead of sleeping for a fixed time.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCondQueueCloseRejectsPutImmediately(t *testing.T) {
+	cq := NewCondQueue[int](4)
+
+	if err := cq.Close(); err != nil {
+		t.Fatalf("Close() = %v, want nil", err)
+	}
+	if err := cq.Put(1); err != ErrDisposed {
+		t.Fatalf("Put() after Close = %v, want ErrDisposed", err)
+	}
+	if err := cq.TryPut(1); err != ErrDisposed {
+		t.Fatalf("TryPut() after Close = %v, want ErrDisposed", err)
+	}
+}
+
+func TestCondQueueCloseLeavesBufferAloneButRejectsGet(t *testing.T) {
+	cq := NewCondQueue[int](4)
+	cq.Put(1)
+	cq.Put(2)
+
+	if err := cq.Close(); err != nil {
+		t.Fatalf("Close() = %v, want nil", err)
+	}
+	if got := cq.Len(); got != 2 {
+		t.Fatalf("Len() after hard Close = %v, want 2 (buffer left alone)", got)
+	}
+	if _, err := cq.Get(); err != ErrDisposed {
+		t.Fatalf("Get() after hard Close = %v, want ErrDisposed", err)
+	}
+	if _, err := cq.TryGet(); err != ErrDisposed {
+		t.Fatalf("TryGet() after hard Close = %v, want ErrDisposed", err)
+	}
+	if _, ok := cq.Poll(); ok {
+		t.Fatal("Poll() after hard Close returned a value, want false")
+	}
+	if _, ok := cq.Peek(); ok {
+		t.Fatal("Peek() after hard Close returned a value, want false")
+	}
+}
+
+func TestCondQueueCloseDrainDrainsThenDisposes(t *testing.T) {
+	cq := NewCondQueue[int](4)
+	cq.Put(1)
+	cq.Put(2)
+
+	if err := cq.CloseDrain(); err != nil {
+		t.Fatalf("CloseDrain() = %v, want nil", err)
+	}
+	if err := cq.Put(3); err != ErrDisposed {
+		t.Fatalf("Put() after CloseDrain = %v, want ErrDisposed", err)
+	}
+
+	if v, ok := cq.Peek(); !ok || v != 1 {
+		t.Fatalf("Peek() while draining = (%v, %v), want (1, true)", v, ok)
+	}
+	if v, ok := cq.Poll(); !ok || v != 1 {
+		t.Fatalf("Poll() while draining = (%v, %v), want (1, true)", v, ok)
+	}
+	if v, err := cq.Get(); err != nil || v != 2 {
+		t.Fatalf("Get() while draining = (%v, %v), want (2, nil)", v, err)
+	}
+	if _, err := cq.Get(); err != ErrDisposed {
+		t.Fatalf("Get() once drained = %v, want ErrDisposed", err)
+	}
+}
+
+func TestCondQueueCloseUnblocksWaitingGet(t *testing.T) {
+	cq := NewCondQueue[int](1)
+	stats := cq.(*CondQueue[int])
+	result := make(chan error, 1)
+
+	go func() {
+		_, err := cq.Get()
+		result <- err
+	}()
+
+	waitFor(t, time.Second, func() bool { return stats.BlockedConsumers() == 1 })
+
+	cq.Close()
+
+	select {
+	case err := <-result:
+		if err != ErrDisposed {
+			t.Fatalf("blocked Get() unblocked with %v, want ErrDisposed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("blocked Get() was not unblocked by Close()")
+	}
+}
+
+func TestCondQueueCloseUnblocksWaitingPut(t *testing.T) {
+	cq := NewCondQueue[int](1)
+	stats := cq.(*CondQueue[int])
+	cq.Put(1) // fill the queue so the next Put blocks
+
+	result := make(chan error, 1)
+	go func() {
+		result <- cq.Put(2)
+	}()
+
+	waitFor(t, time.Second, func() bool { return stats.BlockedProducers() == 1 })
+
+	cq.Close()
+
+	select {
+	case err := <-result:
+		if err != ErrDisposed {
+			t.Fatalf("blocked Put() unblocked with %v, want ErrDisposed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("blocked Put() was not unblocked by Close()")
+	}
+}
@@ -0,0 +1,127 @@
+package queue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingObserver records how many times each Observer method fires,
+// guarded by a mutex since the test goroutine reads the counts while
+// a queue call on another goroutine may be updating them.
+type countingObserver struct {
+	mu               sync.Mutex
+	puts, gets       int
+	fulls, empties   int
+	blocks, unblocks int
+}
+
+func (o *countingObserver) OnPut()     { o.mu.Lock(); o.puts++; o.mu.Unlock() }
+func (o *countingObserver) OnGet()     { o.mu.Lock(); o.gets++; o.mu.Unlock() }
+func (o *countingObserver) OnBlock()   { o.mu.Lock(); o.blocks++; o.mu.Unlock() }
+func (o *countingObserver) OnUnblock() { o.mu.Lock(); o.unblocks++; o.mu.Unlock() }
+func (o *countingObserver) OnFull()    { o.mu.Lock(); o.fulls++; o.mu.Unlock() }
+func (o *countingObserver) OnEmpty()   { o.mu.Lock(); o.empties++; o.mu.Unlock() }
+
+func (o *countingObserver) snapshot() (fulls, empties, blocks, unblocks int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.fulls, o.empties, o.blocks, o.unblocks
+}
+
+func TestCondQueueObserverFiresOnPutTimeoutBlocking(t *testing.T) {
+	obs := &countingObserver{}
+	cq := NewCondQueueWithObserver[int](1, obs)
+	cq.Put(1) // fill the queue so PutTimeout has to block
+
+	if err := cq.PutTimeout(2, 20*time.Millisecond); err == nil {
+		t.Fatal("PutTimeout() on a full queue = nil, want a timeout error")
+	}
+
+	fulls, _, blocks, unblocks := obs.snapshot()
+	if fulls == 0 {
+		t.Fatal("PutTimeout() blocking on a full queue never fired OnFull")
+	}
+	if blocks == 0 || unblocks == 0 {
+		t.Fatalf("PutTimeout() blocking = (blocks=%d, unblocks=%d), want both > 0", blocks, unblocks)
+	}
+}
+
+func TestCondQueueObserverFiresOnGetTimeoutBlocking(t *testing.T) {
+	obs := &countingObserver{}
+	cq := NewCondQueueWithObserver[int](1, obs)
+
+	if _, err := cq.GetTimeout(20 * time.Millisecond); err == nil {
+		t.Fatal("GetTimeout() on an empty queue = nil, want a timeout error")
+	}
+
+	_, empties, blocks, unblocks := obs.snapshot()
+	if empties == 0 {
+		t.Fatal("GetTimeout() blocking on an empty queue never fired OnEmpty")
+	}
+	if blocks == 0 || unblocks == 0 {
+		t.Fatalf("GetTimeout() blocking = (blocks=%d, unblocks=%d), want both > 0", blocks, unblocks)
+	}
+}
+
+func TestCondQueueObserverFiresOnPutNBlocking(t *testing.T) {
+	obs := &countingObserver{}
+	cq := NewCondQueueWithObserver[int](1, obs)
+	bq := cq.(*CondQueue[int])
+	bq.Put(1) // fill the queue so PutN has to block
+
+	unblocked := make(chan struct{})
+	go func() {
+		bq.PutN([]int{2})
+		close(unblocked)
+	}()
+
+	waitFor(t, time.Second, func() bool {
+		fulls, _, _, _ := obs.snapshot()
+		return fulls > 0
+	})
+
+	bq.Get() // frees a slot so the blocked PutN can proceed
+
+	select {
+	case <-unblocked:
+	case <-time.After(time.Second):
+		t.Fatal("PutN() never returned after a slot freed up")
+	}
+
+	_, _, blocks, unblocks := obs.snapshot()
+	if blocks == 0 || unblocks == 0 {
+		t.Fatalf("PutN() blocking = (blocks=%d, unblocks=%d), want both > 0", blocks, unblocks)
+	}
+}
+
+func TestCondQueueObserverFiresOnGetNBlocking(t *testing.T) {
+	obs := &countingObserver{}
+	cq := NewCondQueueWithObserver[int](4, obs)
+	bq := cq.(*CondQueue[int])
+
+	unblocked := make(chan struct{})
+	go func() {
+		dst := make([]int, 1)
+		bq.GetN(dst, 1)
+		close(unblocked)
+	}()
+
+	waitFor(t, time.Second, func() bool {
+		_, empties, _, _ := obs.snapshot()
+		return empties > 0
+	})
+
+	bq.Put(1) // makes a value available so the blocked GetN can proceed
+
+	select {
+	case <-unblocked:
+	case <-time.After(time.Second):
+		t.Fatal("GetN() never returned after a value became available")
+	}
+
+	_, _, blocks, unblocks := obs.snapshot()
+	if blocks == 0 || unblocks == 0 {
+		t.Fatalf("GetN() blocking = (blocks=%d, unblocks=%d), want both > 0", blocks, unblocks)
+	}
+}
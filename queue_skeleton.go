@@ -3,27 +3,34 @@ package queue
 import (
 	"errors"
 	"sync"
+	"time"
 )
 
 // Skeleton is a type of queue that uses a mutex and condition
 // variable to implement the BoundedQueue interface.
 // this version is a skeleton illustrating the mutual exclusion
 // but has no backing data structure. It will fail the tests
-type Skeleton struct {
+type Skeleton[T any] struct {
 	// -- some data structure for backing the queue
 	length   int
 	capacity int
+	disposed bool // set by Close/CloseDrain; rejects Put, gates Get
+	draining bool // true once CloseDrain lets Get drain what remains
 	mtx sync.Mutex      // a mutex for mutual exclusion
 	cvr *sync.Cond       // a condition variable for controlling mutations to the queue
 }
 
 // TryPut adds an element onto the tail queue
 // if the queue is full, an error is returned
-func (skel *Skeleton) TryPut(value interface{}) error {
+func (skel *Skeleton[T]) TryPut(value T) error {
 	// local the mutex
 	skel.cvr.L.Lock();
 	defer skel.cvr.L.Unlock()
 
+	if skel.disposed {
+		return ErrDisposed
+	}
+
 	// is queue full ?
 	if skel.length == skel.capacity {
 		// return an error
@@ -37,99 +44,280 @@ func (skel *Skeleton) TryPut(value interface{}) error {
 
 	// signal a waiter if any
 	skel.cvr.Signal()
-	
+
 	// no error
 	return nil
-} 
+}
 
 // Put adds an element onto the tail queue
 // if the queue is full the function blocks
-func (skel *Skeleton) Put(value interface{})  {
+func (skel *Skeleton[T]) Put(value T) error {
 	// local the mutex
 	skel.cvr.L.Lock()
 	defer skel.cvr.L.Unlock()
 
 
 	// block until a value is in the queue
-	for skel.length == skel.capacity {
+	for skel.length == skel.capacity && !skel.disposed {
 		// releast and wait
 		skel.cvr.Wait()
 	}
-	
+
+	if skel.disposed {
+		return ErrDisposed
+	}
+
 	// queue has room, add it at the tail
 	// -- add to the tail
 	skel.length++
 
 	// signal a waiter if any
 	skel.cvr.Signal()
-} 
+
+	return nil
+}
+
+// PutTimeout adds an element onto the tail queue, blocking until
+// there is room or the timeout elapses.
+func (skel *Skeleton[T]) PutTimeout(value T, d time.Duration) error {
+	skel.cvr.L.Lock()
+	defer skel.cvr.L.Unlock()
+
+	if skel.disposed {
+		return ErrDisposed
+	}
+
+	if skel.length == skel.capacity {
+		timedOut := false
+		timer := time.AfterFunc(d, func() {
+			skel.cvr.L.Lock()
+			timedOut = true
+			skel.cvr.L.Unlock()
+			skel.cvr.Broadcast()
+		})
+		defer timer.Stop()
+
+		for skel.length == skel.capacity && !timedOut && !skel.disposed {
+			skel.cvr.Wait()
+		}
+
+		if skel.disposed {
+			return ErrDisposed
+		}
+
+		if timedOut {
+			return errors.New("put timed out")
+		}
+	}
+
+	// -- add to the tail
+	skel.length++
+
+	skel.cvr.Signal()
+
+	return nil
+}
 
 // Get returns an element from the head of the queue
-// if the queue is empty,the caller blocks
-func (skel *Skeleton) Get() interface{} {
-	var value interface{}
+// if the queue is empty,the caller blocks. Once the queue is
+// disposed it returns ErrDisposed: immediately for a hard Close, or
+// once the buffer is empty for a CloseDrain.
+func (skel *Skeleton[T]) Get() (T, error) {
+	var value T
 
 	// lock the mutex
 	skel.cvr.L.Lock()
 	defer skel.cvr.L.Unlock()
 
+	if skel.disposed && !skel.draining {
+		return value, ErrDisposed
+	}
+
 	// block until a value is in the queue
 	for skel.length == 0 {
+		if skel.disposed {
+			return value, ErrDisposed
+		}
 		// releast and wait
 		skel.cvr.Wait()
 	}
 
 	// at this point there is at least one item in the queue
 	// -- get from the head
-	value = 0
 	skel.length--
 
-	return value
+	skel.cvr.Signal()
+
+	return value, nil
 }
 
 // TryGet attempts to get a value
 // if the queue is empty returns an error
-func (skel *Skeleton) TryGet() (interface{}, error) {
-	var value interface{}
+func (skel *Skeleton[T]) TryGet() (T, error) {
+	var value T
 	var err error
 
 	// lock the mutex
 	skel.cvr.L.Lock()
 	defer skel.cvr.L.Unlock()
 
+	if skel.disposed && !skel.draining {
+		return value, ErrDisposed
+	}
+
 	// does the queue have elements?
 	if skel.length > 0 {
 		// -- get from the head
-		value = 0
 		skel.length--
+	} else if skel.disposed {
+		return value, ErrDisposed
 	} else {
-		value = nil
 		err = errors.New("queue is empty");
 	}
-	
+
 	// unlock the mutex
 	return value, err
 }
 
-// Len is the current number of elements in the queue 
-func (skel *Skeleton) Len() int {
+// GetTimeout removes and returns the value at the head of the queue,
+// blocking until a value is available or the timeout elapses.
+func (skel *Skeleton[T]) GetTimeout(d time.Duration) (T, error) {
+	skel.cvr.L.Lock()
+	defer skel.cvr.L.Unlock()
+
+	var value T
+
+	if skel.disposed && !skel.draining {
+		return value, ErrDisposed
+	}
+
+	if skel.length == 0 {
+		timedOut := false
+		timer := time.AfterFunc(d, func() {
+			skel.cvr.L.Lock()
+			timedOut = true
+			skel.cvr.L.Unlock()
+			skel.cvr.Broadcast()
+		})
+		defer timer.Stop()
+
+		for skel.length == 0 && !timedOut && !skel.disposed {
+			skel.cvr.Wait()
+		}
+
+		if skel.length == 0 && skel.disposed {
+			return value, ErrDisposed
+		}
+
+		if timedOut {
+			return value, errors.New("get timed out")
+		}
+	}
+
+	// -- get from the head
+	skel.length--
+
+	skel.cvr.Signal()
+
+	return value, nil
+}
+
+// Poll removes and returns the value at the head of the queue without
+// blocking. The second return value is false if the queue was empty.
+func (skel *Skeleton[T]) Poll() (T, bool) {
+	skel.cvr.L.Lock()
+	defer skel.cvr.L.Unlock()
+
+	var value T
+	if skel.length == 0 {
+		return value, false
+	}
+
+	// -- get from the head
+	skel.length--
+
+	skel.cvr.Signal()
+
+	return value, true
+}
+
+// Peek returns the value at the head of the queue without removing
+// it. The second return value is false if the queue was empty. There
+// is no backing data structure to read from, so this always returns
+// the zero value.
+func (skel *Skeleton[T]) Peek() (T, bool) {
+	skel.cvr.L.Lock()
+	defer skel.cvr.L.Unlock()
+
+	var value T
+	if skel.length == 0 {
+		return value, false
+	}
+
+	return value, true
+}
+
+// Drain empties the queue and returns what it held, waking all
+// blocked producers. There is no backing data structure, so the
+// returned slice only reflects the count that was drained.
+func (skel *Skeleton[T]) Drain() []T {
+	skel.cvr.L.Lock()
+	defer skel.cvr.L.Unlock()
+
+	out := make([]T, skel.length)
+	skel.length = 0
+
+	skel.cvr.Broadcast()
+
+	return out
+}
+
+// Len is the current number of elements in the queue
+func (skel *Skeleton[T]) Len() int {
 	return skel.length
 }
 
 // Cap is the maximum number of elements the queue can hold
-func (skel *Skeleton) Cap() int {
+func (skel *Skeleton[T]) Cap() int {
 	return skel.capacity
 }
 
+// Close disposes of the queue. All in-flight and future Put/Get calls
+// return ErrDisposed, and every blocked goroutine is woken.
+func (skel *Skeleton[T]) Close() error {
+	skel.mtx.Lock()
+	skel.disposed = true
+	skel.draining = false
+	skel.mtx.Unlock()
+
+	skel.cvr.Broadcast()
+
+	return nil
+}
+
+// CloseDrain disposes of the queue but lets Get continue to succeed
+// until the buffer is empty before it starts returning ErrDisposed.
+// Put still returns ErrDisposed right away.
+func (skel *Skeleton[T]) CloseDrain() error {
+	skel.mtx.Lock()
+	skel.disposed = true
+	skel.draining = true
+	skel.mtx.Unlock()
+
+	skel.cvr.Broadcast()
+
+	return nil
+}
+
 // String
-func (skel *Skeleton) String() string {return ""}
+func (skel *Skeleton[T]) String() string {return ""}
 
 // NewSkeletonQueue is a factory for creating bounded queues
 // that use a condition variable and circular buffer. It returns
-// an instance of pointer to BoundedQueue
-func NewSkeletonQueue(size int) BoundedQueue {
-	var skel Skeleton
-	
+// an instance of BoundedQueue.
+func NewSkeletonQueue[T any](size int) BoundedQueue[T] {
+	var skel Skeleton[T]
+
 	// allocate the whole slice during init
 	skel.length = 0
 	skel.capacity = size
@@ -137,4 +325,4 @@ func NewSkeletonQueue(size int) BoundedQueue {
 	skel.cvr = sync.NewCond(&skel.mtx)
 
 	return &skel
-}
\ No newline at end of file
+}